@@ -0,0 +1,46 @@
+package main
+
+import "github.com/nsf/termbox-go"
+
+// AboutScreen is a static keybinding reference, reachable from MainScreen
+// with '?'. It's also the simplest possible Screen implementation - a
+// template for future screens like a report/burndown view.
+type AboutScreen struct{}
+
+func newAboutScreen() *AboutScreen {
+	return &AboutScreen{}
+}
+
+func (s *AboutScreen) Enter() {}
+func (s *AboutScreen) Leave() {}
+
+func (s *AboutScreen) Render(width, height int) {
+	termbox.Clear(termbox.ColorDefault, termbox.ColorDefault)
+
+	drawLine(0, 0, width, "About nf - Esc to go back", termbox.ColorWhite|termbox.AttrBold, termbox.ColorBlue)
+
+	lines := []string{
+		"",
+		"j/k        navigate            Shift+j/k  change priority",
+		"a/s        add task/subtask    d          delete task",
+		"Enter      task details        x          toggle complete",
+		"space      expand/collapse     /          filter",
+		"u          undo                Ctrl-R     redo",
+		"t          todo.txt sync       Ctrl-G     cancel pending op",
+		"i          todo.txt import     g/G        jump to top/bottom",
+		"Ctrl-D/U   half-page scroll    ?          this screen",
+		"q          quit",
+	}
+	for i, line := range lines {
+		drawLine(0, 2+i, width, line, termbox.ColorDefault, termbox.ColorDefault)
+	}
+
+	termbox.Flush()
+}
+
+func (s *AboutScreen) HandleKey(ev termbox.Event) ScreenID {
+	if ev.Key == termbox.KeyEsc {
+		return ScreenPop
+	}
+	return ScreenNone
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// filterTerm is one whitespace-separated piece of a filter query. scope
+// restricts which field the term is matched against; anchor and negate
+// mirror a (small) slice of fzf's extended-search syntax.
+type filterTerm struct {
+	negate bool
+	anchor byte // 0, '^' (prefix), or '$' (suffix)
+	scope  byte // 0 (everything), '+' (projects), or '@' (contexts)
+	text   string
+}
+
+func parseFilterTerms(query string) []filterTerm {
+	var terms []filterTerm
+	for _, raw := range strings.Fields(query) {
+		var t filterTerm
+		if strings.HasPrefix(raw, "!") {
+			t.negate = true
+			raw = raw[1:]
+		}
+		if len(raw) > 0 && (raw[0] == '+' || raw[0] == '@') {
+			t.scope = raw[0]
+			raw = raw[1:]
+		} else if len(raw) > 0 && (raw[0] == '^' || raw[0] == '$') {
+			t.anchor = raw[0]
+			raw = raw[1:]
+		}
+		t.text = raw
+		terms = append(terms, t)
+	}
+	return terms
+}
+
+// candidates returns the searchable strings a term should be checked
+// against, depending on its scope.
+func (t filterTerm) candidates(task *Task) []string {
+	switch t.scope {
+	case '+':
+		return task.Projects
+	case '@':
+		return task.Contexts
+	default:
+		fields := []string{task.Title}
+		for _, p := range task.Projects {
+			fields = append(fields, "+"+p)
+		}
+		for _, c := range task.Contexts {
+			fields = append(fields, "@"+c)
+		}
+		for k, v := range task.Tags {
+			fields = append(fields, k+":"+v)
+		}
+		return []string{strings.Join(fields, " ")}
+	}
+}
+
+// matches reports whether task satisfies term and, if so, how well.
+func (t filterTerm) matches(task *Task) (bool, int) {
+	best := math.MinInt
+	matched := false
+
+	for _, c := range t.candidates(task) {
+		switch t.anchor {
+		case '^':
+			if strings.HasPrefix(strings.ToLower(c), strings.ToLower(t.text)) {
+				matched = true
+				best = 50
+			}
+		case '$':
+			if strings.HasSuffix(strings.ToLower(c), strings.ToLower(t.text)) {
+				matched = true
+				best = 50
+			}
+		default:
+			if score, ok := fuzzyScore(t.text, c); ok {
+				matched = true
+				if score > best {
+					best = score
+				}
+			}
+		}
+	}
+
+	if t.negate {
+		return !matched, 0
+	}
+	if !matched {
+		return false, 0
+	}
+	return true, best
+}
+
+// matchTask evaluates every term against task, ANDing them together, and
+// returns the combined score used to rank results.
+func matchTask(task *Task, terms []filterTerm) (bool, int) {
+	total := 0
+	for _, term := range terms {
+		ok, score := term.matches(task)
+		if !ok {
+			return false, 0
+		}
+		total += score
+	}
+	return true, total
+}
+
+// filterTasks narrows tasks down to the ones matching query, best match
+// first. query is empty-safe: an empty term list matches everything.
+func filterTasks(tasks []*Task, query string) []*Task {
+	terms := parseFilterTerms(query)
+
+	type scored struct {
+		task  *Task
+		score int
+	}
+	var results []scored
+	for _, task := range tasks {
+		if ok, score := matchTask(task, terms); ok {
+			results = append(results, scored{task, score})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].score > results[j].score
+	})
+
+	out := make([]*Task, len(results))
+	for i, r := range results {
+		out[i] = r.task
+	}
+	return out
+}
+
+// fuzzyScore is a small fzf-style subsequence scorer: pattern's characters
+// must appear in text in order, with bonuses for consecutive matches and
+// word-boundary/camelCase starts, and a penalty for each gap once matching
+// has begun. The penalty can outweigh the bonuses on a long gappy match, so
+// a zero or negative score doesn't mean "no match" - ok reports whether
+// pattern is actually a subsequence of text; score is only meaningful when
+// ok is true.
+func fuzzyScore(pattern, text string) (score int, ok bool) {
+	if pattern == "" {
+		return 1, true
+	}
+
+	lowerPattern := strings.ToLower(pattern)
+	lowerText := strings.ToLower(text)
+
+	pi, consecutive := 0, 0
+	for ti := 0; ti < len(lowerText) && pi < len(lowerPattern); ti++ {
+		if lowerText[ti] != lowerPattern[pi] {
+			if pi > 0 {
+				score--
+			}
+			consecutive = 0
+			continue
+		}
+
+		bonus := 1
+		if consecutive > 0 {
+			bonus += consecutive * 2
+		}
+		if ti == 0 || isWordBoundary(text, ti) {
+			bonus += 3
+		}
+		score += bonus
+		consecutive++
+		pi++
+	}
+
+	if pi < len(lowerPattern) {
+		return 0, false
+	}
+	return score, true
+}
+
+// isWordBoundary reports whether the rune at byte offset i in text starts a
+// new "word": after a separator, or a camelCase capital following a
+// lowercase letter.
+func isWordBoundary(text string, i int) bool {
+	prev := rune(text[i-1])
+	cur := rune(text[i])
+
+	switch prev {
+	case ' ', '_', '-', '+', '@', ':', '/':
+		return true
+	}
+	return unicode.IsUpper(cur) && unicode.IsLower(prev)
+}
+
+func pluralSuffix(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "es"
+}
@@ -0,0 +1,11 @@
+package main
+
+import "testing"
+
+func TestFilterMatchesGappyLowScoringSubsequence(t *testing.T) {
+	task := &Task{Title: "Refactor scheduler"}
+	ok, _ := matchTask(task, parseFilterTerms("rr"))
+	if !ok {
+		t.Fatalf("expected \"rr\" to match %q even with a negative fuzzy score", task.Title)
+	}
+}
@@ -0,0 +1,367 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HistoryOp identifies which kind of reversible operation a HistoryEntry
+// records.
+type HistoryOp string
+
+const (
+	OpAdd      HistoryOp = "add"
+	OpDelete   HistoryOp = "delete"
+	OpEdit     HistoryOp = "edit"
+	OpPriority HistoryOp = "priority"
+
+	// OpExpand and OpReparent are reserved for operations that don't exist
+	// yet (collapsing a subtree and moving a task to a new parent aren't
+	// separate user actions today), but are defined here so the journal
+	// doesn't need a breaking change once they are.
+	OpExpand   HistoryOp = "expand"
+	OpReparent HistoryOp = "reparent"
+)
+
+// TaskSnapshot is the slice of a task's row the journal needs to replay an
+// operation's inverse: enough to re-insert a deleted task or restore an
+// edited one, but not the full row (projects/contexts/tags/completed are
+// not journaled). Children holds the direct children a deleted task had, so
+// undoing the delete can restore them too (deleteTask removes a task and
+// its direct children together); every other op leaves it nil.
+type TaskSnapshot struct {
+	ID        int
+	Title     string
+	Priority  int
+	ParentID  *int
+	CreatedAt time.Time
+	Children  []TaskSnapshot
+}
+
+// historyFieldSep and historyRecordSep encode a TaskSnapshot.Children list
+// into the tasks_history row's before_children column: fields within a
+// child are joined with historyFieldSep, children with historyRecordSep.
+// Titles come from free-form user input and can contain anything a plain
+// comma-join would mangle (see listSep in tags.go for the same problem),
+// so these use ASCII separators a title can't type.
+const (
+	historyFieldSep  = "\x1f"
+	historyRecordSep = "\x1e"
+)
+
+// encodeChildSnapshots serializes before.Children for storage; see
+// historyFieldSep.
+func encodeChildSnapshots(children []TaskSnapshot) string {
+	parts := make([]string, 0, len(children))
+	for _, c := range children {
+		parts = append(parts, strings.Join([]string{
+			strconv.Itoa(c.ID),
+			c.Title,
+			strconv.Itoa(c.Priority),
+			strconv.Itoa(*c.ParentID),
+			c.CreatedAt.Format("2006-01-02 15:04:05"),
+		}, historyFieldSep))
+	}
+	return strings.Join(parts, historyRecordSep)
+}
+
+// decodeChildSnapshots is encodeChildSnapshots' inverse.
+func decodeChildSnapshots(s string) []TaskSnapshot {
+	if s == "" {
+		return nil
+	}
+	var out []TaskSnapshot
+	for _, rec := range strings.Split(s, historyRecordSep) {
+		fields := strings.Split(rec, historyFieldSep)
+		if len(fields) != 5 {
+			continue
+		}
+		id, _ := strconv.Atoi(fields[0])
+		priority, _ := strconv.Atoi(fields[2])
+		parentID, _ := strconv.Atoi(fields[3])
+		createdAt, _ := time.Parse("2006-01-02 15:04:05", fields[4])
+		out = append(out, TaskSnapshot{ID: id, Title: fields[1], Priority: priority, ParentID: &parentID, CreatedAt: createdAt})
+	}
+	return out
+}
+
+// HistoryEntry records one applied TaskManager write. Before is nil for an
+// add (there was no prior row); After is nil for a delete (there's no row
+// left). Undo applies Before (or deletes After.ID); redo applies After (or
+// re-inserts Before).
+type HistoryEntry struct {
+	Seq    int64
+	Op     HistoryOp
+	Before *TaskSnapshot
+	After  *TaskSnapshot
+}
+
+// loadHistory reads the persisted journal into tm.history so undo/redo
+// survive a restart. It runs in NewTaskManager before the scheduler
+// goroutine exists, so it doesn't lock tm.mu (see loadTasks).
+func (tm *TaskManager) loadHistory() error {
+	rows, err := tm.db.Query(`
+		SELECT seq, op, before_id, before_title, before_priority, before_parent_id, before_created_at, before_children,
+		       after_id, after_title, after_priority, after_parent_id, after_created_at
+		FROM tasks_history
+		ORDER BY seq ASC
+	`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	tm.history = make([]HistoryEntry, 0)
+	for rows.Next() {
+		var e HistoryEntry
+		var op string
+		var beforeID, beforeParentID, afterID, afterParentID sql.NullInt64
+		var beforeTitle, beforeCreatedAt, afterTitle, afterCreatedAt sql.NullString
+		var beforePriority, afterPriority sql.NullInt64
+		var beforeChildren sql.NullString
+
+		err := rows.Scan(&e.Seq, &op, &beforeID, &beforeTitle, &beforePriority, &beforeParentID, &beforeCreatedAt, &beforeChildren,
+			&afterID, &afterTitle, &afterPriority, &afterParentID, &afterCreatedAt)
+		if err != nil {
+			return err
+		}
+		e.Op = HistoryOp(op)
+		e.Before = snapshotFromRow(beforeID, beforeTitle, beforePriority, beforeParentID, beforeCreatedAt)
+		if e.Before != nil {
+			e.Before.Children = decodeChildSnapshots(beforeChildren.String)
+		}
+		e.After = snapshotFromRow(afterID, afterTitle, afterPriority, afterParentID, afterCreatedAt)
+		tm.history = append(tm.history, e)
+	}
+
+	tm.historyPos = len(tm.history)
+	return nil
+}
+
+// snapshotFromRow rebuilds a *TaskSnapshot from the nullable columns a
+// before/after side of a tasks_history row decodes into, returning nil when
+// the side wasn't recorded (id is the NULL sentinel for "no row").
+func snapshotFromRow(id sql.NullInt64, title sql.NullString, priority sql.NullInt64, parentID sql.NullInt64, createdAt sql.NullString) *TaskSnapshot {
+	if !id.Valid {
+		return nil
+	}
+	s := &TaskSnapshot{ID: int(id.Int64), Title: title.String, Priority: int(priority.Int64)}
+	if parentID.Valid {
+		pid := int(parentID.Int64)
+		s.ParentID = &pid
+	}
+	s.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt.String)
+	return s
+}
+
+// maxHistoryEntries bounds the undo/redo journal, both tm.history and the
+// persisted tasks_history table, so a long session's journal doesn't grow
+// without limit (see maxLogEntries in log.go for the same pattern).
+const maxHistoryEntries = 200
+
+// recordHistory persists one journal entry and appends it to the in-memory
+// ring buffer, dropping any undone-then-superseded entries ahead of
+// historyPos (and the tasks_history rows behind them, so a redo branch that
+// got overwritten by a new write can't reappear after a restart) and then
+// trimming the oldest entries past maxHistoryEntries from both the
+// in-memory slice and the tasks_history table. Callers already hold tm.mu.
+func (tm *TaskManager) recordHistory(op HistoryOp, before, after *TaskSnapshot) error {
+	if tm.historyPos < len(tm.history) {
+		var lastKeptSeq int64
+		if tm.historyPos > 0 {
+			lastKeptSeq = tm.history[tm.historyPos-1].Seq
+		}
+		if _, err := tm.db.Exec("DELETE FROM tasks_history WHERE seq > ?", lastKeptSeq); err != nil {
+			return err
+		}
+	}
+
+	var beforeChildren string
+	if before != nil {
+		beforeChildren = encodeChildSnapshots(before.Children)
+	}
+
+	res, err := tm.db.Exec(`
+		INSERT INTO tasks_history
+			(op, before_id, before_title, before_priority, before_parent_id, before_created_at, before_children,
+			 after_id, after_title, after_priority, after_parent_id, after_created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		string(op),
+		snapshotIDArg(before), snapshotTitleArg(before), snapshotPriorityArg(before), snapshotParentArg(before), snapshotCreatedAtArg(before), beforeChildren,
+		snapshotIDArg(after), snapshotTitleArg(after), snapshotPriorityArg(after), snapshotParentArg(after), snapshotCreatedAtArg(after),
+	)
+	if err != nil {
+		return err
+	}
+	seq, _ := res.LastInsertId()
+
+	tm.history = append(tm.history[:tm.historyPos], HistoryEntry{Seq: seq, Op: op, Before: before, After: after})
+	tm.historyPos = len(tm.history)
+
+	if len(tm.history) > maxHistoryEntries {
+		drop := len(tm.history) - maxHistoryEntries
+		tm.history = tm.history[drop:]
+		tm.historyPos = len(tm.history)
+
+		if _, err := tm.db.Exec(
+			"DELETE FROM tasks_history WHERE seq NOT IN (SELECT seq FROM tasks_history ORDER BY seq DESC LIMIT ?)",
+			maxHistoryEntries,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func snapshotIDArg(s *TaskSnapshot) interface{} {
+	if s == nil {
+		return nil
+	}
+	return s.ID
+}
+
+func snapshotTitleArg(s *TaskSnapshot) interface{} {
+	if s == nil {
+		return nil
+	}
+	return s.Title
+}
+
+func snapshotPriorityArg(s *TaskSnapshot) interface{} {
+	if s == nil {
+		return nil
+	}
+	return s.Priority
+}
+
+func snapshotParentArg(s *TaskSnapshot) interface{} {
+	if s == nil || s.ParentID == nil {
+		return nil
+	}
+	return *s.ParentID
+}
+
+func snapshotCreatedAtArg(s *TaskSnapshot) interface{} {
+	if s == nil {
+		return nil
+	}
+	return s.CreatedAt.Format("2006-01-02 15:04:05")
+}
+
+// findTaskByID walks the task tree looking for id, used to snapshot a row
+// before a write changes or removes it.
+func findTaskByID(tasks []*Task, id int) *Task {
+	for _, t := range tasks {
+		if t.ID == id {
+			return t
+		}
+		if found := findTaskByID(t.Children, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// undo applies the inverse of the most recently applied (and not yet
+// undone) journal entry. See addTask re: locking.
+func (tm *TaskManager) undo() error {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	if tm.historyPos == 0 {
+		tm.logf(LogWarn, "Nothing to undo")
+		return fmt.Errorf("nothing to undo")
+	}
+	entry := tm.history[tm.historyPos-1]
+
+	if err := tm.applySnapshot(entry.Before, entry.After); err != nil {
+		tm.logf(LogError, "Undo failed: %v", err)
+		return err
+	}
+
+	tm.historyPos--
+	tm.logf(LogInfo, "Undid %s", entry.Op)
+	tm.loadTasksLocked()
+	tm.rebuildFlatView()
+	tm.syncTodoTxtIfEnabled()
+	return nil
+}
+
+func (tm *TaskManager) queueUndo() *Condition {
+	return tm.queue(func(ctx context.Context) error {
+		return tm.undo()
+	})
+}
+
+// redo re-applies the most recently undone journal entry. See addTask re: locking.
+func (tm *TaskManager) redo() error {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	if tm.historyPos >= len(tm.history) {
+		tm.logf(LogWarn, "Nothing to redo")
+		return fmt.Errorf("nothing to redo")
+	}
+	entry := tm.history[tm.historyPos]
+
+	if err := tm.applySnapshot(entry.After, entry.Before); err != nil {
+		tm.logf(LogError, "Redo failed: %v", err)
+		return err
+	}
+
+	tm.historyPos++
+	tm.logf(LogInfo, "Redid %s", entry.Op)
+	tm.loadTasksLocked()
+	tm.rebuildFlatView()
+	tm.syncTodoTxtIfEnabled()
+	return nil
+}
+
+func (tm *TaskManager) queueRedo() *Condition {
+	return tm.queue(func(ctx context.Context) error {
+		return tm.redo()
+	})
+}
+
+// applySnapshot makes the database match want, given other (the snapshot on
+// the opposite side of the same entry): want == nil means the row (and, per
+// other.Children, any subtasks deleteTask removed alongside it) should not
+// exist, otherwise the row identified by want.ID should have want's
+// title/priority, inserting it (and want.Children, for the undo-a-delete
+// case) first if it's missing.
+func (tm *TaskManager) applySnapshot(want, other *TaskSnapshot) error {
+	if want == nil {
+		_, err := tm.db.Exec("DELETE FROM tasks WHERE id = ? OR parent_id = ?", other.ID, other.ID)
+		return err
+	}
+
+	var exists bool
+	if err := tm.db.QueryRow("SELECT EXISTS(SELECT 1 FROM tasks WHERE id = ?)", want.ID).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		if _, err := tm.db.Exec(
+			"INSERT INTO tasks (id, title, priority, parent_id, created_at) VALUES (?, ?, ?, ?, ?)",
+			want.ID, want.Title, want.Priority, snapshotParentArg(want), want.CreatedAt.Format("2006-01-02 15:04:05"),
+		); err != nil {
+			return err
+		}
+		for _, child := range want.Children {
+			if _, err := tm.db.Exec(
+				"INSERT INTO tasks (id, title, priority, parent_id, created_at) VALUES (?, ?, ?, ?, ?)",
+				child.ID, child.Title, child.Priority, snapshotParentArg(&child), child.CreatedAt.Format("2006-01-02 15:04:05"),
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	_, err := tm.db.Exec("UPDATE tasks SET title = ?, priority = ? WHERE id = ?", want.Title, want.Priority, want.ID)
+	return err
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestChildSnapshotsRoundTrip(t *testing.T) {
+	parentID := 1
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	children := []TaskSnapshot{
+		{ID: 2, Title: "sub,one", Priority: 50, ParentID: &parentID, CreatedAt: created},
+		{ID: 3, Title: "sub two", Priority: 60, ParentID: &parentID, CreatedAt: created},
+	}
+
+	got := decodeChildSnapshots(encodeChildSnapshots(children))
+	if !reflect.DeepEqual(got, children) {
+		t.Fatalf("round-trip mismatch: got %+v, want %+v", got, children)
+	}
+}
+
+func TestDecodeChildSnapshotsEmpty(t *testing.T) {
+	if got := decodeChildSnapshots(""); got != nil {
+		t.Fatalf("decodeChildSnapshots(\"\") = %+v, want nil", got)
+	}
+}
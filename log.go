@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nsf/termbox-go"
+)
+
+// LogLevel orders log pane entries from least to most alarming. The '1'-'4'
+// keys set logMinLevel to one of these, hiding anything below it.
+type LogLevel int
+
+const (
+	LogDebug LogLevel = iota
+	LogInfo
+	LogWarn
+	LogError
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LogDebug:
+		return "DEBUG"
+	case LogInfo:
+		return "INFO"
+	case LogWarn:
+		return "WARN"
+	case LogError:
+		return "ERROR"
+	default:
+		return "?"
+	}
+}
+
+// color is the level's display color in the log pane: errors and warnings
+// stand out, info is the default foreground, and debug uses the same
+// muted cyan the rest of the UI already uses for secondary text.
+func (l LogLevel) color() termbox.Attribute {
+	switch l {
+	case LogError:
+		return termbox.ColorRed | termbox.AttrBold
+	case LogWarn:
+		return termbox.ColorYellow
+	case LogDebug:
+		return termbox.ColorCyan
+	default:
+		return termbox.ColorDefault
+	}
+}
+
+// LogEntry is one line recorded in the TaskManager's log pane.
+type LogEntry struct {
+	Level LogLevel
+	Time  time.Time
+	Msg   string
+}
+
+// maxLogEntries bounds the in-memory ring buffer so a long session doesn't
+// grow it without limit.
+const maxLogEntries = 500
+
+// logf appends a structured entry to the log buffer, replacing the single
+// statusMsg string every TaskManager write used to format directly.
+// Callers already hold tm.mu (it's called from the same locked write
+// methods statusMsg assignments used to live in).
+func (tm *TaskManager) logf(level LogLevel, format string, args ...interface{}) {
+	tm.logs = append(tm.logs, LogEntry{Level: level, Time: time.Now(), Msg: fmt.Sprintf(format, args...)})
+	if len(tm.logs) > maxLogEntries {
+		tm.logs = tm.logs[len(tm.logs)-maxLogEntries:]
+	}
+}
+
+// lastLogEntry is the most recent entry, shown as a one-line flash at the
+// top of MainScreen even when the log pane is closed.
+func (tm *TaskManager) lastLogEntry() (LogEntry, bool) {
+	if len(tm.logs) == 0 {
+		return LogEntry{}, false
+	}
+	return tm.logs[len(tm.logs)-1], true
+}
+
+// visibleLogEntries applies the pane's minimum-level and substring filters,
+// oldest first (so the pane reads top-to-bottom like a scrolling console).
+func (tm *TaskManager) visibleLogEntries() []LogEntry {
+	var out []LogEntry
+	for _, e := range tm.logs {
+		if e.Level < tm.logMinLevel {
+			continue
+		}
+		if tm.logFilter != "" && !strings.Contains(strings.ToLower(e.Msg), strings.ToLower(tm.logFilter)) {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// logPaneHeight is how many rows renderMain reserves at the bottom of the
+// screen for the log pane, scaled down on short terminals so it never
+// crowds out the whole task list.
+func logPaneHeight(height int) int {
+	h := height / 3
+	if h > 12 {
+		h = 12
+	}
+	if h < 4 {
+		h = 4
+	}
+	return h
+}
+
+// renderLogPane draws the pane's header, its filtered entries (most recent
+// at the bottom), and the filter-input line when logFilterMode is active,
+// into the bottom logPaneHeight rows of the screen. Caller already holds
+// tm.mu (renderMain does).
+func (tm *TaskManager) renderLogPane(width, height int) {
+	paneHeight := logPaneHeight(height)
+	top := height - paneHeight
+
+	header := fmt.Sprintf("Log (min: %s%s) - 1:debug 2:info 3:warn 4:error, /: filter, L/Esc: close", tm.logMinLevel, logFilterSuffix(tm.logFilter))
+	drawLine(0, top, width, header, termbox.ColorWhite|termbox.AttrBold, termbox.ColorBlue)
+
+	entries := tm.visibleLogEntries()
+	rows := paneHeight - 1
+	start := 0
+	if len(entries) > rows {
+		start = len(entries) - rows
+	}
+	for i, e := range entries[start:] {
+		line := fmt.Sprintf("[%s] %-5s %s", e.Time.Format("15:04:05"), e.Level, e.Msg)
+		drawLine(0, top+1+i, width, line, e.Level.color(), termbox.ColorDefault)
+	}
+
+	if tm.logFilterMode {
+		drawLine(0, height-1, width, "Log filter: "+tm.logFilter, termbox.ColorBlack, termbox.ColorYellow)
+	}
+}
+
+// logFilterSuffix renders the active substring filter into the pane header,
+// or nothing when there isn't one.
+func logFilterSuffix(filter string) string {
+	if filter == "" {
+		return ""
+	}
+	return fmt.Sprintf(", filter: %q", filter)
+}
+
+// handleLogPaneMode handles input while the log pane is open and not
+// currently capturing its filter query (see handleLogFilterMode).
+func (tm *TaskManager) handleLogPaneMode(ev termbox.Event) bool {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	switch ev.Key {
+	case termbox.KeyEsc:
+		tm.logPane = false
+		return false
+	}
+
+	switch ev.Ch {
+	case 'L':
+		tm.logPane = false
+	case '1':
+		tm.logMinLevel = LogDebug
+	case '2':
+		tm.logMinLevel = LogInfo
+	case '3':
+		tm.logMinLevel = LogWarn
+	case '4':
+		tm.logMinLevel = LogError
+	case '/':
+		tm.logFilterMode = true
+	}
+	return false
+}
+
+// handleLogFilterMode handles input while typing the log pane's substring
+// filter, mirroring handleFilterMode's editing keys.
+func (tm *TaskManager) handleLogFilterMode(ev termbox.Event) bool {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	switch ev.Key {
+	case termbox.KeyEsc:
+		tm.logFilterMode = false
+		tm.logFilter = ""
+	case termbox.KeyEnter:
+		tm.logFilterMode = false
+	case termbox.KeyBackspace, termbox.KeyBackspace2:
+		if len(tm.logFilter) > 0 {
+			tm.logFilter = tm.logFilter[:len(tm.logFilter)-1]
+		}
+	case termbox.KeySpace:
+		tm.logFilter += " "
+	default:
+		if ev.Ch != 0 {
+			tm.logFilter += string(ev.Ch)
+		}
+	}
+	return false
+}
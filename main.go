@@ -1,12 +1,14 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -22,22 +24,52 @@ type Task struct {
 	ParentID   *int
 	Children   []*Task
 	IsExpanded bool
+	Projects   []string
+	Contexts   []string
+	Tags       map[string]string
+	Completed  bool
+	Notes      string
+	DueDate    *time.Time
 }
 
 // TaskManager handles all task operations
 type TaskManager struct {
+	// mu guards tasks/flatView/currentIndex/scrollOffset/logs against the
+	// scheduler goroutine. Scheduler ops (addTask and friends, ImportTodoTxt,
+	// undo/redo) take it only to read inputs from and write results back
+	// into those fields, never across the db.ExecContext call itself - that
+	// runs unlocked so a slow write can't starve the UI thread's render
+	// (RLock) or input handling (Lock), and so Ctrl-G's cancel reaches
+	// tm.currentOp without waiting on the op it's meant to interrupt.
+	mu sync.RWMutex
+
 	db            *sql.DB
 	tasks         []*Task
 	currentIndex  int
 	scrollOffset  int
 	flatView      []*Task
-	editMode      bool
-	editBuffer    string
-	statusMsg     string
 	inputMode     string // "add", "addsubtask", "edit", ""
 	inputStep     int    // 0 = title, 1 = priority
 	inputTitle    string
 	inputPriority string
+	syncTodoTxt   bool
+	todoTxtPath   string
+	filterMode    bool
+	filterActive  bool
+	filterQuery   string
+
+	scheduler *Scheduler
+	currentOp *Condition
+	quit      chan struct{}
+
+	history    []HistoryEntry // ring buffer of applied writes; history[:historyPos] is the undo stack, history[historyPos:] the redo stack
+	historyPos int
+
+	logs          []LogEntry // bounded ring buffer; see log.go
+	logPane       bool
+	logFilterMode bool
+	logMinLevel   LogLevel
+	logFilter     string
 }
 
 // NewTaskManager creates a new task manager instance
@@ -47,11 +79,17 @@ func NewTaskManager() *TaskManager {
 		currentIndex: 0,
 		scrollOffset: 0,
 		flatView:     make([]*Task, 0),
+		todoTxtPath:  "tasks.txt",
+		scheduler:    newScheduler(),
+		quit:         make(chan struct{}),
 	}
 
 	if err := tm.initDB(); err != nil {
 		log.Fatal("Failed to initialize database:", err)
 	}
+	if err := tm.loadHistory(); err != nil {
+		log.Fatal("Failed to load undo history:", err)
+	}
 
 	tm.loadTasks()
 	tm.rebuildFlatView()
@@ -77,15 +115,71 @@ func (tm *TaskManager) initDB() error {
 		FOREIGN KEY (parent_id) REFERENCES tasks (id) ON DELETE CASCADE
 	);`
 
-	_, err = tm.db.Exec(createTableSQL)
-	return err
+	if _, err = tm.db.Exec(createTableSQL); err != nil {
+		return err
+	}
+
+	historyTableSQL := `
+	CREATE TABLE IF NOT EXISTS tasks_history (
+		seq INTEGER PRIMARY KEY AUTOINCREMENT,
+		op TEXT NOT NULL,
+		before_id INTEGER,
+		before_title TEXT,
+		before_priority INTEGER,
+		before_parent_id INTEGER,
+		before_created_at TEXT,
+		before_children TEXT,
+		after_id INTEGER,
+		after_title TEXT,
+		after_priority INTEGER,
+		after_parent_id INTEGER,
+		after_created_at TEXT
+	);`
+
+	if _, err = tm.db.Exec(historyTableSQL); err != nil {
+		return err
+	}
+
+	return tm.migrateSchema()
+}
+
+// migrateSchema adds columns introduced after the initial tasks table so
+// existing tasks.db files pick them up with default values instead of
+// forcing a fresh database.
+func (tm *TaskManager) migrateSchema() error {
+	columns := []string{
+		"ALTER TABLE tasks ADD COLUMN projects TEXT DEFAULT ''",
+		"ALTER TABLE tasks ADD COLUMN contexts TEXT DEFAULT ''",
+		"ALTER TABLE tasks ADD COLUMN tags TEXT DEFAULT ''",
+		"ALTER TABLE tasks ADD COLUMN completed INTEGER DEFAULT 0",
+		"ALTER TABLE tasks ADD COLUMN notes TEXT DEFAULT ''",
+		"ALTER TABLE tasks ADD COLUMN due_date TEXT",
+		"ALTER TABLE tasks_history ADD COLUMN before_children TEXT DEFAULT ''",
+	}
+	for _, ddl := range columns {
+		if _, err := tm.db.Exec(ddl); err != nil && !strings.Contains(err.Error(), "duplicate column") {
+			return err
+		}
+	}
+	return nil
 }
 
-// loadTasks loads all tasks from database
+// loadTasks loads all tasks from database. It locks tm.mu itself, so it
+// must only be called from places that aren't already holding the lock
+// (NewTaskManager, before the scheduler goroutine exists). Everywhere else
+// that needs a reload while already holding the lock (the queued DB ops
+// below) calls loadTasksLocked directly.
 func (tm *TaskManager) loadTasks() error {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	return tm.loadTasksLocked()
+}
+
+// loadTasksLocked is loadTasks' body; the caller must hold tm.mu.
+func (tm *TaskManager) loadTasksLocked() error {
 	rows, err := tm.db.Query(`
-		SELECT id, title, priority, created_at, parent_id 
-		FROM tasks 
+		SELECT id, title, priority, created_at, parent_id, projects, contexts, tags, completed, notes, due_date
+		FROM tasks
 		ORDER BY priority DESC, created_at ASC
 	`)
 	if err != nil {
@@ -100,13 +194,26 @@ func (tm *TaskManager) loadTasks() error {
 		task := &Task{Children: make([]*Task, 0), IsExpanded: true}
 		var parentID sql.NullInt64
 		var createdAt string
+		var projects, contexts, tags, notes string
+		var completed int
+		var dueDate sql.NullString
 
-		err := rows.Scan(&task.ID, &task.Title, &task.Priority, &createdAt, &parentID)
+		err := rows.Scan(&task.ID, &task.Title, &task.Priority, &createdAt, &parentID, &projects, &contexts, &tags, &completed, &notes, &dueDate)
 		if err != nil {
 			return err
 		}
 
 		task.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
+		task.Projects = decodeStringList(projects)
+		task.Contexts = decodeStringList(contexts)
+		task.Tags = decodeTags(tags)
+		task.Completed = completed != 0
+		task.Notes = notes
+		if dueDate.Valid && dueDate.String != "" {
+			if d, err := time.Parse(todoTxtDateFormat, dueDate.String); err == nil {
+				task.DueDate = &d
+			}
+		}
 
 		if parentID.Valid {
 			task.ParentID = new(int)
@@ -147,10 +254,18 @@ func (tm *TaskManager) sortTasks(tasks []*Task) {
 	})
 }
 
-// rebuildFlatView creates a flat view of tasks for navigation
+// rebuildFlatView creates a flat view of tasks for navigation. When a
+// filter is active it replaces the hierarchical view with a flat,
+// best-match-first list drawn from every task regardless of expand state.
+// The caller must hold tm.mu (or, as in NewTaskManager, run before the
+// scheduler goroutine exists).
 func (tm *TaskManager) rebuildFlatView() {
 	tm.flatView = make([]*Task, 0)
-	tm.buildFlatView(tm.tasks, 0)
+	if tm.filterActive && tm.filterQuery != "" {
+		tm.flatView = filterTasks(flattenForExport(tm.tasks), tm.filterQuery)
+	} else {
+		tm.buildFlatView(tm.tasks, 0)
+	}
 
 	if tm.currentIndex >= len(tm.flatView) {
 		tm.currentIndex = len(tm.flatView) - 1
@@ -199,70 +314,196 @@ func (tm *TaskManager) buildFlatView(tasks []*Task, depth int) {
 	}
 }
 
-// addTask adds a new task
-func (tm *TaskManager) addTask(title string, priority int, parentID *int) error {
+// addTask adds a new task. Any +project, @context, or key:value tokens in
+// title are pulled out into the task's structured fields. It runs as a
+// queued Scheduler op (see queueAddTask) and checks ctx so Ctrl-G can
+// actually abandon the write (see queue). The db.ExecContext call runs with
+// tm.mu unlocked - see the package comment on lock scope above TaskManager
+// - so a slow write doesn't freeze the UI thread's render or input handling;
+// tm.mu is only taken to read/write the guarded fields before and after.
+func (tm *TaskManager) addTask(ctx context.Context, title string, priority int, parentID *int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	plainTitle, projects, contexts, tags := parseTaskMetadata(title)
+
 	var result sql.Result
 	var err error
 
 	if parentID != nil {
-		result, err = tm.db.Exec(
-			"INSERT INTO tasks (title, priority, parent_id) VALUES (?, ?, ?)",
-			title, priority, *parentID,
+		result, err = tm.db.ExecContext(ctx,
+			"INSERT INTO tasks (title, priority, parent_id, projects, contexts, tags) VALUES (?, ?, ?, ?, ?, ?)",
+			plainTitle, priority, *parentID, encodeStringList(projects), encodeStringList(contexts), encodeTags(tags),
 		)
 	} else {
-		result, err = tm.db.Exec(
-			"INSERT INTO tasks (title, priority) VALUES (?, ?)",
-			title, priority,
+		result, err = tm.db.ExecContext(ctx,
+			"INSERT INTO tasks (title, priority, projects, contexts, tags) VALUES (?, ?, ?, ?, ?)",
+			plainTitle, priority, encodeStringList(projects), encodeStringList(contexts), encodeTags(tags),
 		)
 	}
 
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
 	if err != nil {
+		tm.logf(LogError, "Error adding task: %v", err)
 		return err
 	}
 
 	id, _ := result.LastInsertId()
-	tm.statusMsg = fmt.Sprintf("Added task: %s (ID: %d)", title, id)
+	tm.logf(LogInfo, "Added task: %s (ID: %d)", plainTitle, id)
 
-	tm.loadTasks()
+	tm.recordHistory(OpAdd, nil, &TaskSnapshot{ID: int(id), Title: plainTitle, Priority: priority, ParentID: parentID, CreatedAt: time.Now()})
+	tm.loadTasksLocked()
 	tm.rebuildFlatView()
+	tm.syncTodoTxtIfEnabled()
 	return nil
 }
 
-// deleteTask deletes a task and its children
-func (tm *TaskManager) deleteTask(taskID int) error {
-	_, err := tm.db.Exec("DELETE FROM tasks WHERE id = ? OR parent_id = ?", taskID, taskID)
+// queueAddTask queues addTask on the scheduler and returns immediately so
+// the UI thread isn't blocked on the write.
+func (tm *TaskManager) queueAddTask(title string, priority int, parentID *int) *Condition {
+	return tm.queue(func(ctx context.Context) error {
+		return tm.addTask(ctx, title, priority, parentID)
+	})
+}
+
+// deleteTask deletes a task and its children. See addTask re: locking and
+// ctx.
+func (tm *TaskManager) deleteTask(ctx context.Context, taskID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	tm.mu.RLock()
+	before := findTaskByID(tm.tasks, taskID)
+	tm.mu.RUnlock()
+
+	_, err := tm.db.ExecContext(ctx, "DELETE FROM tasks WHERE id = ? OR parent_id = ?", taskID, taskID)
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
 	if err != nil {
+		tm.logf(LogError, "Error deleting task: %v", err)
 		return err
 	}
 
-	tm.statusMsg = fmt.Sprintf("Deleted task ID: %d", taskID)
-	tm.loadTasks()
+	tm.logf(LogInfo, "Deleted task ID: %d", taskID)
+	if before != nil {
+		children := make([]TaskSnapshot, 0, len(before.Children))
+		for _, c := range before.Children {
+			children = append(children, TaskSnapshot{ID: c.ID, Title: c.Title, Priority: c.Priority, ParentID: c.ParentID, CreatedAt: c.CreatedAt})
+		}
+		tm.recordHistory(OpDelete, &TaskSnapshot{ID: before.ID, Title: before.Title, Priority: before.Priority, ParentID: before.ParentID, CreatedAt: before.CreatedAt, Children: children}, nil)
+	}
+	tm.loadTasksLocked()
 	tm.rebuildFlatView()
+	tm.syncTodoTxtIfEnabled()
 	return nil
 }
 
-// updateTask updates a task's title and priority
-func (tm *TaskManager) updateTask(taskID int, title string, priority int) error {
-	_, err := tm.db.Exec(
-		"UPDATE tasks SET title = ?, priority = ? WHERE id = ?",
-		title, priority, taskID,
+func (tm *TaskManager) queueDeleteTask(taskID int) *Condition {
+	return tm.queue(func(ctx context.Context) error {
+		return tm.deleteTask(ctx, taskID)
+	})
+}
+
+// updateTask updates a task's title and priority. Like addTask, +project,
+// @context, and key:value tokens in title are re-extracted into the
+// structured fields. See addTask re: locking and ctx.
+func (tm *TaskManager) updateTask(ctx context.Context, taskID int, title string, priority int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	tm.mu.RLock()
+	before := findTaskByID(tm.tasks, taskID)
+	tm.mu.RUnlock()
+
+	plainTitle, projects, contexts, tags := parseTaskMetadata(title)
+
+	_, err := tm.db.ExecContext(ctx,
+		"UPDATE tasks SET title = ?, priority = ?, projects = ?, contexts = ?, tags = ? WHERE id = ?",
+		plainTitle, priority, encodeStringList(projects), encodeStringList(contexts), encodeTags(tags), taskID,
 	)
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
 	if err != nil {
+		tm.logf(LogError, "Error updating task: %v", err)
 		return err
 	}
 
-	tm.statusMsg = fmt.Sprintf("Updated task ID: %d", taskID)
-	tm.loadTasks()
+	tm.logf(LogInfo, "Updated task ID: %d", taskID)
+	if before != nil {
+		tm.recordHistory(OpEdit,
+			&TaskSnapshot{ID: before.ID, Title: before.Title, Priority: before.Priority, ParentID: before.ParentID, CreatedAt: before.CreatedAt},
+			&TaskSnapshot{ID: before.ID, Title: plainTitle, Priority: priority, ParentID: before.ParentID, CreatedAt: before.CreatedAt},
+		)
+	}
+	tm.loadTasksLocked()
+	tm.rebuildFlatView()
+	tm.syncTodoTxtIfEnabled()
+	return nil
+}
+
+func (tm *TaskManager) queueUpdateTask(taskID int, title string, priority int) *Condition {
+	return tm.queue(func(ctx context.Context) error {
+		return tm.updateTask(ctx, taskID, title, priority)
+	})
+}
+
+// updateTaskCompleted toggles a task's completion marker. See addTask re:
+// locking and ctx.
+func (tm *TaskManager) updateTaskCompleted(ctx context.Context, taskID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	_, err := tm.db.ExecContext(ctx, "UPDATE tasks SET completed = NOT completed WHERE id = ?", taskID)
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	if err != nil {
+		tm.logf(LogError, "Error toggling completion: %v", err)
+		return err
+	}
+
+	tm.logf(LogInfo, "Toggled completion for task ID: %d", taskID)
+	tm.loadTasksLocked()
 	tm.rebuildFlatView()
+	tm.syncTodoTxtIfEnabled()
 	return nil
 }
 
-// updateTaskPriority updates only a task's priority
-func (tm *TaskManager) updateTaskPriority(taskID int, priorityChange int) error {
+func (tm *TaskManager) queueUpdateTaskCompleted(taskID int) *Condition {
+	return tm.queue(func(ctx context.Context) error {
+		return tm.updateTaskCompleted(ctx, taskID)
+	})
+}
+
+// updateTaskPriority updates only a task's priority. See addTask re: locking
+// and ctx.
+func (tm *TaskManager) updateTaskPriority(ctx context.Context, taskID int, priorityChange int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	tm.mu.RLock()
+	before := findTaskByID(tm.tasks, taskID)
+	tm.mu.RUnlock()
+
 	// First get current priority
 	var currentPriority int
-	err := tm.db.QueryRow("SELECT priority FROM tasks WHERE id = ?", taskID).Scan(&currentPriority)
+	err := tm.db.QueryRowContext(ctx, "SELECT priority FROM tasks WHERE id = ?", taskID).Scan(&currentPriority)
 	if err != nil {
+		tm.mu.Lock()
+		tm.logf(LogError, "Error reading priority: %v", err)
+		tm.mu.Unlock()
 		return err
 	}
 
@@ -276,17 +517,122 @@ func (tm *TaskManager) updateTaskPriority(taskID int, priorityChange int) error
 		newPriority = 100
 	}
 
-	_, err = tm.db.Exec("UPDATE tasks SET priority = ? WHERE id = ?", newPriority, taskID)
+	_, err = tm.db.ExecContext(ctx, "UPDATE tasks SET priority = ? WHERE id = ?", newPriority, taskID)
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
 	if err != nil {
+		tm.logf(LogError, "Error updating priority: %v", err)
 		return err
 	}
 
-	tm.statusMsg = fmt.Sprintf("Updated task priority: %d -> %d", currentPriority, newPriority)
-	tm.loadTasks()
+	tm.logf(LogInfo, "Updated task priority: %d -> %d", currentPriority, newPriority)
+	if before != nil {
+		tm.recordHistory(OpPriority,
+			&TaskSnapshot{ID: before.ID, Title: before.Title, Priority: currentPriority, ParentID: before.ParentID, CreatedAt: before.CreatedAt},
+			&TaskSnapshot{ID: before.ID, Title: before.Title, Priority: newPriority, ParentID: before.ParentID, CreatedAt: before.CreatedAt},
+		)
+	}
+	tm.loadTasksLocked()
+	tm.rebuildFlatView()
+	tm.syncTodoTxtIfEnabled()
+	return nil
+}
+
+func (tm *TaskManager) queueUpdateTaskPriority(taskID int, priorityChange int) *Condition {
+	return tm.queue(func(ctx context.Context) error {
+		return tm.updateTaskPriority(ctx, taskID, priorityChange)
+	})
+}
+
+// updateTaskNotes replaces a task's free-form notes, edited from TaskScreen.
+// Not journaled: the undo/redo history only covers title/priority/parent_id
+// (see history.go). See addTask re: locking and ctx.
+func (tm *TaskManager) updateTaskNotes(ctx context.Context, taskID int, notes string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	_, err := tm.db.ExecContext(ctx, "UPDATE tasks SET notes = ? WHERE id = ?", notes, taskID)
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	if err != nil {
+		tm.logf(LogError, "Error updating notes: %v", err)
+		return err
+	}
+
+	tm.logf(LogInfo, "Updated notes for task ID: %d", taskID)
+	tm.loadTasksLocked()
 	tm.rebuildFlatView()
+	tm.syncTodoTxtIfEnabled()
 	return nil
 }
 
+func (tm *TaskManager) queueUpdateTaskNotes(taskID int, notes string) *Condition {
+	return tm.queue(func(ctx context.Context) error {
+		return tm.updateTaskNotes(ctx, taskID, notes)
+	})
+}
+
+// updateTaskDueDate sets or clears a task's due date, edited from
+// TaskScreen. Not journaled; see updateTaskNotes. See addTask re: ctx.
+func (tm *TaskManager) updateTaskDueDate(ctx context.Context, taskID int, due *time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var dueArg interface{}
+	if due != nil {
+		dueArg = due.Format(todoTxtDateFormat)
+	}
+
+	_, err := tm.db.ExecContext(ctx, "UPDATE tasks SET due_date = ? WHERE id = ?", dueArg, taskID)
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	if err != nil {
+		tm.logf(LogError, "Error updating due date: %v", err)
+		return err
+	}
+
+	tm.logf(LogInfo, "Updated due date for task ID: %d", taskID)
+	tm.loadTasksLocked()
+	tm.rebuildFlatView()
+	tm.syncTodoTxtIfEnabled()
+	return nil
+}
+
+func (tm *TaskManager) queueUpdateTaskDueDate(taskID int, due *time.Time) *Condition {
+	return tm.queue(func(ctx context.Context) error {
+		return tm.updateTaskDueDate(ctx, taskID, due)
+	})
+}
+
+// queue submits fn to the scheduler and remembers the resulting Condition
+// as the in-flight op Ctrl-G cancels. Callers must already hold tm.mu (it's
+// called from the locked handle*Mode methods), but queue itself doesn't
+// touch tm's guarded fields so there's no recursive locking.
+func (tm *TaskManager) queue(fn func(ctx context.Context) error) *Condition {
+	cond := tm.scheduler.Queue(fn)
+	tm.currentOp = cond
+	return cond
+}
+
+// syncTodoTxtIfEnabled re-exports tasks.txt after a write when the user has
+// toggled sync-on-save on with the 't' keybinding.
+func (tm *TaskManager) syncTodoTxtIfEnabled() {
+	if !tm.syncTodoTxt {
+		return
+	}
+	if err := tm.ExportTodoTxt(tm.todoTxtPath); err != nil {
+		tm.logf(LogError, "todo.txt sync failed: %v", err)
+	}
+}
+
 // formatDuration formats time duration for display
 func formatDuration(d time.Duration) string {
 	if d < time.Minute {
@@ -320,14 +666,15 @@ func (tm *TaskManager) getTaskDepth(task *Task) int {
 	return 0
 }
 
-// render renders the task list
-func (tm *TaskManager) render() {
-	termbox.Clear(termbox.ColorDefault, termbox.ColorDefault)
+// renderMain renders the task list. It's MainScreen's Render.
+func (tm *TaskManager) renderMain(width, height int) {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
 
-	width, height := termbox.Size()
+	termbox.Clear(termbox.ColorDefault, termbox.ColorDefault)
 
 	// Header with better styling
-	header := "Task Manager - j/k: navigate, Shift+j: priority -1, Shift+k: priority +1, a: add, s: subtask, d: delete, e: edit, space: toggle, q: quit"
+	header := "Task Manager - j/k: navigate, Shift+j/k: priority, a: add, s: subtask, d: delete, Enter: details, x: complete, u: undo, Ctrl-R: redo, t: todo.txt sync, i: todo.txt import, /: filter, L: log, ?: about, Ctrl-G: cancel op, space: toggle, q: quit"
 	for i, r := range header {
 		if i >= width {
 			break
@@ -335,20 +682,61 @@ func (tm *TaskManager) render() {
 		termbox.SetCell(i, 0, r, termbox.ColorWhite|termbox.AttrBold, termbox.ColorBlue)
 	}
 
-	// Status message with better contrast
-	if tm.statusMsg != "" {
-		for i, r := range tm.statusMsg {
+	// Spinner in the top-right corner while a queued op is pending, so
+	// imports/bulk writes read as "in progress" instead of the UI looking
+	// frozen.
+	if pending := tm.scheduler.Pending(); pending > 0 {
+		frames := []rune{'|', '/', '-', '\\'}
+		frame := frames[int(time.Now().UnixNano()/100_000_000)%len(frames)]
+		spinner := fmt.Sprintf(" %c %d pending ", frame, pending)
+		start := width - len(spinner)
+		if start < 0 {
+			start = 0
+		}
+		for i, r := range spinner {
+			if start+i >= width {
+				break
+			}
+			termbox.SetCell(start+i, 0, r, termbox.ColorWhite|termbox.AttrBold, termbox.ColorRed)
+		}
+	}
+
+	// Last log entry as a one-line flash, color-coded by level - the full
+	// history is one 'L' press away in the log pane (see renderLogPane).
+	if last, ok := tm.lastLogEntry(); ok {
+		bg := termbox.ColorGreen
+		if last.Level == LogError {
+			bg = termbox.ColorRed
+		} else if last.Level == LogWarn {
+			bg = termbox.ColorYellow
+		}
+		for i, r := range last.Msg {
+			if i >= width {
+				break
+			}
+			termbox.SetCell(i, 1, r, termbox.ColorBlack, bg)
+		}
+	}
+
+	// Filter summary: stays visible even after the query line (below) is
+	// dismissed with Enter, so the match count doesn't disappear with it.
+	if tm.filterActive {
+		matches := fmt.Sprintf("Filter: %s (%d match%s) - / to edit, Esc to clear", tm.filterQuery, len(tm.flatView), pluralSuffix(len(tm.flatView)))
+		for i, r := range matches {
 			if i >= width {
 				break
 			}
-			termbox.SetCell(i, 1, r, termbox.ColorBlack, termbox.ColorGreen)
+			termbox.SetCell(i, 2, r, termbox.ColorCyan, termbox.ColorDefault)
 		}
 	}
 
 	// Tasks
 	startY := 3
-	_, height = termbox.Size()
-	maxVisibleTasks := height - startY - 1
+	panelHeight := 0
+	if tm.logPane {
+		panelHeight = logPaneHeight(height)
+	}
+	maxVisibleTasks := height - startY - 1 - panelHeight
 
 	startIdx := tm.scrollOffset
 	endIdx := tm.scrollOffset + maxVisibleTasks
@@ -368,7 +756,10 @@ func (tm *TaskManager) render() {
 			fg = termbox.ColorBlack | termbox.AttrBold
 		}
 
-		depth := tm.getTaskDepth(task)
+		depth := 0
+		if !tm.filterActive {
+			depth = tm.getTaskDepth(task)
+		}
 		indent := strings.Repeat("  ", depth)
 
 		// Better visual indicators
@@ -409,7 +800,11 @@ func (tm *TaskManager) render() {
 		}
 
 		// Render the task line
-		line := fmt.Sprintf("%s%s%s%s", indent, expansion, prefix, task.Title)
+		checkbox := "[ ] "
+		if task.Completed {
+			checkbox = "[x] "
+		}
+		line := fmt.Sprintf("%s%s%s%s%s", indent, expansion, prefix, checkbox, task.Title)
 		for j, r := range line {
 			if j >= width-25 { // Leave space for priority and time
 				break
@@ -443,15 +838,32 @@ func (tm *TaskManager) render() {
 		}
 	}
 
-	// Edit mode with better styling
-	if tm.editMode {
-		editY := height - 2
-		editPrompt := "Edit (title:priority): " + tm.editBuffer
-		for i, r := range editPrompt {
+	if tm.logPane {
+		tm.renderLogPane(width, height)
+	} else if tm.filterMode {
+		inputY := height - 3
+		prompt := "Filter (/): " + tm.filterQuery
+
+		for j := 0; j < width; j++ {
+			termbox.SetCell(j, inputY, ' ', termbox.ColorDefault, termbox.ColorDefault)
+		}
+		for i, r := range prompt {
+			if i >= width {
+				break
+			}
+			termbox.SetCell(i, inputY, r, termbox.ColorBlack, termbox.ColorYellow)
+		}
+
+		helpY := height - 2
+		helpText := "Enter to apply, Esc to clear - prefix terms with + @ ! ^ $"
+		for j := 0; j < width; j++ {
+			termbox.SetCell(j, helpY, ' ', termbox.ColorDefault, termbox.ColorDefault)
+		}
+		for i, r := range helpText {
 			if i >= width {
 				break
 			}
-			termbox.SetCell(i, editY, r, termbox.ColorBlack, termbox.ColorYellow)
+			termbox.SetCell(i, helpY, r, termbox.ColorWhite, termbox.ColorBlue)
 		}
 	} else if tm.inputMode != "" {
 		inputY := height - 3
@@ -470,6 +882,8 @@ func (tm *TaskManager) render() {
 			} else {
 				prompt = fmt.Sprintf("Add Subtask - Title: %s, Priority (1-100, default 50): %s", tm.inputTitle, tm.inputPriority)
 			}
+		case "import":
+			prompt = "Import todo.txt - Path: " + tm.inputTitle
 		}
 
 		// Clear the input line
@@ -500,17 +914,12 @@ func (tm *TaskManager) render() {
 	termbox.Flush()
 }
 
-// handleInput handles keyboard input and returns true if should quit
-func (tm *TaskManager) handleInput() bool {
+// handleInput polls the next termbox event and hands it to the active
+// screen, returning true if the program should quit.
+func (tm *TaskManager) handleInput(sm *ScreenManager) bool {
 	switch ev := termbox.PollEvent(); ev.Type {
 	case termbox.EventKey:
-		if tm.editMode {
-			return tm.handleEditMode(ev)
-		} else if tm.inputMode != "" {
-			return tm.handleInputMode(ev)
-		} else {
-			return tm.handleNormalMode(ev)
-		}
+		return sm.HandleKey(ev)
 	}
 	return false
 }
@@ -536,17 +945,31 @@ func (tm *TaskManager) scrollHalfPageUp() {
 	tm.adjustScroll()
 }
 
-// handleNormalMode handles input in normal mode and returns true if should quit
-func (tm *TaskManager) handleNormalMode(ev termbox.Event) bool {
-	tm.statusMsg = ""
+// handleNormalMode handles input in normal mode and returns the ScreenID
+// the MainScreen's HandleKey should report to the ScreenManager.
+func (tm *TaskManager) handleNormalMode(ev termbox.Event, ms *MainScreen) ScreenID {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
 
 	switch ev.Key {
 	case termbox.KeyCtrlC:
-		return true
+		return ScreenQuit
+	case termbox.KeyEnter:
+		if len(tm.flatView) > 0 && tm.currentIndex < len(tm.flatView) {
+			ms.pendingTaskID = tm.flatView[tm.currentIndex].ID
+			return ScreenTask
+		}
 	case termbox.KeyCtrlD:
 		tm.scrollHalfPageDown()
 	case termbox.KeyCtrlU:
 		tm.scrollHalfPageUp()
+	case termbox.KeyCtrlG:
+		if tm.currentOp != nil {
+			tm.currentOp.Cancel()
+			tm.logf(LogWarn, "Cancelling in-flight operation...")
+		}
+	case termbox.KeyCtrlR:
+		tm.queueRedo()
 	case termbox.KeySpace:
 		if len(tm.flatView) > 0 && tm.currentIndex < len(tm.flatView) {
 			task := tm.flatView[tm.currentIndex]
@@ -559,7 +982,9 @@ func (tm *TaskManager) handleNormalMode(ev termbox.Event) bool {
 
 	switch ev.Ch {
 	case 'q':
-		return true
+		return ScreenQuit
+	case '?':
+		return ScreenAbout
 	case 'j':
 		// Regular j: move down
 		if tm.currentIndex < len(tm.flatView)-1 {
@@ -576,13 +1001,13 @@ func (tm *TaskManager) handleNormalMode(ev termbox.Event) bool {
 		// Capital J (Shift+j): decrease priority by 1
 		if len(tm.flatView) > 0 && tm.currentIndex < len(tm.flatView) {
 			task := tm.flatView[tm.currentIndex]
-			tm.updateTaskPriority(task.ID, -1)
+			tm.queueUpdateTaskPriority(task.ID, -1)
 		}
 	case 'K':
 		// Capital K (Shift+k): increase priority by 1
 		if len(tm.flatView) > 0 && tm.currentIndex < len(tm.flatView) {
 			task := tm.flatView[tm.currentIndex]
-			tm.updateTaskPriority(task.ID, +1)
+			tm.queueUpdateTaskPriority(task.ID, +1)
 		}
 	case 'g':
 		tm.currentIndex = 0
@@ -605,13 +1030,61 @@ func (tm *TaskManager) handleNormalMode(ev termbox.Event) bool {
 	case 'd':
 		if len(tm.flatView) > 0 && tm.currentIndex < len(tm.flatView) {
 			task := tm.flatView[tm.currentIndex]
-			tm.deleteTask(task.ID)
+			tm.queueDeleteTask(task.ID)
 		}
-	case 'e':
+	case 'u':
+		tm.queueUndo()
+	case 't':
+		tm.syncTodoTxt = !tm.syncTodoTxt
+		if tm.syncTodoTxt {
+			tm.logf(LogInfo, "todo.txt sync on (%s)", tm.todoTxtPath)
+			tm.syncTodoTxtIfEnabled()
+		} else {
+			tm.logf(LogInfo, "todo.txt sync off")
+		}
+	case 'i':
+		tm.inputMode = "import"
+		tm.inputStep = 0
+		tm.inputTitle = tm.todoTxtPath
+	case 'x':
 		if len(tm.flatView) > 0 && tm.currentIndex < len(tm.flatView) {
 			task := tm.flatView[tm.currentIndex]
-			tm.editMode = true
-			tm.editBuffer = fmt.Sprintf("%s:%d", task.Title, task.Priority)
+			tm.queueUpdateTaskCompleted(task.ID)
+		}
+	case '/':
+		tm.filterMode = true
+		tm.filterActive = true
+	case 'L':
+		tm.logPane = true
+	}
+	return ScreenNone
+}
+
+// handleFilterMode handles input while the fuzzy filter bar is open.
+func (tm *TaskManager) handleFilterMode(ev termbox.Event) bool {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	switch ev.Key {
+	case termbox.KeyEsc:
+		tm.filterMode = false
+		tm.filterActive = false
+		tm.filterQuery = ""
+		tm.rebuildFlatView()
+	case termbox.KeyEnter:
+		tm.filterMode = false
+	case termbox.KeyBackspace, termbox.KeyBackspace2:
+		if len(tm.filterQuery) > 0 {
+			tm.filterQuery = tm.filterQuery[:len(tm.filterQuery)-1]
+			tm.rebuildFlatView()
+		}
+	case termbox.KeySpace:
+		tm.filterQuery += " "
+		tm.rebuildFlatView()
+	default:
+		if ev.Ch != 0 {
+			tm.filterQuery += string(ev.Ch)
+			tm.rebuildFlatView()
 		}
 	}
 	return false
@@ -619,6 +1092,9 @@ func (tm *TaskManager) handleNormalMode(ev termbox.Event) bool {
 
 // handleInputMode handles input mode for adding tasks
 func (tm *TaskManager) handleInputMode(ev termbox.Event) bool {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
 	switch ev.Key {
 	case termbox.KeyEsc:
 		tm.inputMode = ""
@@ -626,10 +1102,22 @@ func (tm *TaskManager) handleInputMode(ev termbox.Event) bool {
 		tm.inputTitle = ""
 		tm.inputPriority = ""
 	case termbox.KeyEnter:
-		if tm.inputStep == 0 {
+		if tm.inputMode == "import" {
+			path := tm.inputTitle
+			if path == "" {
+				tm.logf(LogWarn, "todo.txt path cannot be empty")
+				return false
+			}
+			tm.queueImportTodoTxt(path)
+
+			tm.inputMode = ""
+			tm.inputStep = 0
+			tm.inputTitle = ""
+			tm.inputPriority = ""
+		} else if tm.inputStep == 0 {
 			// Move to priority input
 			if tm.inputTitle == "" {
-				tm.statusMsg = "Task title cannot be empty"
+				tm.logf(LogWarn, "Task title cannot be empty")
 				return false
 			}
 			tm.inputStep = 1
@@ -650,10 +1138,7 @@ func (tm *TaskManager) handleInputMode(ev termbox.Event) bool {
 				parentID = &currentTask.ID
 			}
 
-			err := tm.addTask(tm.inputTitle, priority, parentID)
-			if err != nil {
-				tm.statusMsg = fmt.Sprintf("Error adding task: %v", err)
-			}
+			tm.queueAddTask(tm.inputTitle, priority, parentID)
 
 			tm.inputMode = ""
 			tm.inputStep = 0
@@ -688,45 +1173,6 @@ func (tm *TaskManager) handleInputMode(ev termbox.Event) bool {
 	return false
 }
 
-// handleEditMode handles input in edit mode and returns true if should quit
-func (tm *TaskManager) handleEditMode(ev termbox.Event) bool {
-	switch ev.Key {
-	case termbox.KeyEsc:
-		tm.editMode = false
-		tm.editBuffer = ""
-	case termbox.KeyEnter:
-		if len(tm.flatView) > 0 && tm.currentIndex < len(tm.flatView) {
-			task := tm.flatView[tm.currentIndex]
-			parts := strings.SplitN(tm.editBuffer, ":", 2)
-			if len(parts) == 2 {
-				title := strings.TrimSpace(parts[0])
-				priority, err := strconv.Atoi(strings.TrimSpace(parts[1]))
-				if err != nil {
-					priority = task.Priority
-				}
-				if priority < 1 {
-					priority = 1
-				}
-				if priority > 100 {
-					priority = 100
-				}
-				tm.updateTask(task.ID, title, priority)
-			}
-		}
-		tm.editMode = false
-		tm.editBuffer = ""
-	case termbox.KeyBackspace, termbox.KeyBackspace2:
-		if len(tm.editBuffer) > 0 {
-			tm.editBuffer = tm.editBuffer[:len(tm.editBuffer)-1]
-		}
-	default:
-		if ev.Ch != 0 {
-			tm.editBuffer += string(ev.Ch)
-		}
-	}
-	return false
-}
-
 // promptAddTask is now deprecated - keeping for compatibility but not used
 func (tm *TaskManager) promptAddTask(isSubtask bool) {
 	// This function is no longer used - input is now inline
@@ -749,12 +1195,38 @@ func (tm *TaskManager) Run() {
 	}
 	defer termbox.Close()
 
+	go tm.runSpinnerTicker()
+
+	sm := newScreenManager(newMainScreen(tm))
+
 	for {
-		tm.render()
-		if tm.handleInput() {
+		width, height := termbox.Size()
+		sm.Render(width, height)
+		if tm.handleInput(sm) {
 			break
 		}
 	}
+
+	close(tm.quit)
+}
+
+// runSpinnerTicker periodically interrupts the blocking PollEvent call in
+// Run's loop so the header spinner animates while a queued op is pending,
+// instead of freezing until the next real keypress.
+func (tm *TaskManager) runSpinnerTicker() {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if tm.scheduler.Pending() > 0 {
+				termbox.Interrupt()
+			}
+		case <-tm.quit:
+			return
+		}
+	}
 }
 
 func main() {
@@ -0,0 +1,44 @@
+package main
+
+import "github.com/nsf/termbox-go"
+
+// MainScreen is the task list: it's a thin Screen wrapper around
+// TaskManager, which still owns all the list/filter/add-task state and
+// logic (renderMain, handleNormalMode, handleFilterMode, handleInputMode).
+// pendingTaskID carries the selection across to TaskScreen when Enter
+// pushes it (see ScreenManager.HandleKey).
+type MainScreen struct {
+	tm            *TaskManager
+	pendingTaskID int
+}
+
+func newMainScreen(tm *TaskManager) *MainScreen {
+	return &MainScreen{tm: tm}
+}
+
+func (s *MainScreen) Enter() {}
+func (s *MainScreen) Leave() {}
+
+func (s *MainScreen) Render(width, height int) {
+	s.tm.renderMain(width, height)
+}
+
+func (s *MainScreen) HandleKey(ev termbox.Event) ScreenID {
+	tm := s.tm
+	switch {
+	case tm.logFilterMode:
+		tm.handleLogFilterMode(ev)
+		return ScreenNone
+	case tm.logPane:
+		tm.handleLogPaneMode(ev)
+		return ScreenNone
+	case tm.filterMode:
+		tm.handleFilterMode(ev)
+		return ScreenNone
+	case tm.inputMode != "":
+		tm.handleInputMode(ev)
+		return ScreenNone
+	default:
+		return tm.handleNormalMode(ev, s)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/nsf/termbox-go"
+)
+
+// Condition is the handle returned when an operation is queued on the
+// Scheduler. The scheduler goroutine calls Notify once the operation's
+// function has returned; callers block on WaitToFinish for that result, and
+// Cancel lets the UI thread abandon an op via the context passed to it.
+type Condition struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan error
+}
+
+func newCondition() *Condition {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Condition{ctx: ctx, cancel: cancel, done: make(chan error, 1)}
+}
+
+// Ctx returns the context an operation should watch for cancellation.
+func (c *Condition) Ctx() context.Context {
+	return c.ctx
+}
+
+// Notify delivers the operation's result to whoever is waiting on it.
+func (c *Condition) Notify(err error) {
+	c.done <- err
+}
+
+// WaitToFinish blocks until Notify has been called and returns its result.
+func (c *Condition) WaitToFinish() error {
+	return <-c.done
+}
+
+// Cancel requests that the operation stop via its context.
+func (c *Condition) Cancel() {
+	c.cancel()
+}
+
+// schedulerOp pairs a queued function with the Condition used to report its
+// result.
+type schedulerOp struct {
+	fn   func(ctx context.Context) error
+	cond *Condition
+}
+
+// Scheduler runs queued operations one at a time on a dedicated goroutine so
+// the UI thread issuing them never blocks on a database write. It mirrors
+// the coordinator/task pattern used to keep long-running work off a
+// program's main loop.
+type Scheduler struct {
+	ops     chan schedulerOp
+	pending int32 // atomic; read by the header spinner
+}
+
+// newScheduler starts the worker goroutine and returns the Scheduler used to
+// queue work onto it.
+func newScheduler() *Scheduler {
+	s := &Scheduler{ops: make(chan schedulerOp, 32)}
+	go s.run()
+	return s
+}
+
+func (s *Scheduler) run() {
+	for op := range s.ops {
+		err := op.fn(op.cond.Ctx())
+		op.cond.Notify(err)
+		atomic.AddInt32(&s.pending, -1)
+		// Wake the UI's blocking PollEvent so it redraws with the op's
+		// result (and stops the spinner) without waiting for a keypress.
+		termbox.Interrupt()
+	}
+}
+
+// Queue submits fn to run on the scheduler goroutine and returns a handle
+// for waiting on or cancelling it.
+func (s *Scheduler) Queue(fn func(ctx context.Context) error) *Condition {
+	cond := newCondition()
+	atomic.AddInt32(&s.pending, 1)
+	s.ops <- schedulerOp{fn: fn, cond: cond}
+	return cond
+}
+
+// Pending reports how many operations are queued or running.
+func (s *Scheduler) Pending() int {
+	return int(atomic.LoadInt32(&s.pending))
+}
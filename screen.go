@@ -0,0 +1,93 @@
+package main
+
+import "github.com/nsf/termbox-go"
+
+// ScreenID is what Screen.HandleKey returns to tell the ScreenManager what
+// to do next: stay put, pop back to the previous screen, quit the program,
+// or push one of the named screens on top of the stack.
+type ScreenID int
+
+const (
+	ScreenNone ScreenID = iota
+	ScreenPop
+	ScreenQuit
+	ScreenTask
+	ScreenAbout
+)
+
+// Screen is one full-terminal view. MainScreen sits at the bottom of the
+// ScreenManager's stack for the life of the program; TaskScreen and
+// AboutScreen push on top of it and pop back off. A future report/burndown
+// view would plug in the same way: implement Screen, return its ScreenID
+// from wherever it should be reachable.
+type Screen interface {
+	Render(width, height int)
+	HandleKey(ev termbox.Event) ScreenID
+	Enter()
+	Leave()
+}
+
+// ScreenManager stacks Screens; only the top of the stack renders and
+// receives input.
+type ScreenManager struct {
+	stack []Screen
+}
+
+func newScreenManager(initial Screen) *ScreenManager {
+	initial.Enter()
+	return &ScreenManager{stack: []Screen{initial}}
+}
+
+func (sm *ScreenManager) top() Screen {
+	return sm.stack[len(sm.stack)-1]
+}
+
+func (sm *ScreenManager) push(s Screen) {
+	sm.top().Leave()
+	sm.stack = append(sm.stack, s)
+	s.Enter()
+}
+
+func (sm *ScreenManager) pop() {
+	if len(sm.stack) <= 1 {
+		return
+	}
+	sm.top().Leave()
+	sm.stack = sm.stack[:len(sm.stack)-1]
+	sm.top().Enter()
+}
+
+// Render draws the top screen.
+func (sm *ScreenManager) Render(width, height int) {
+	sm.top().Render(width, height)
+}
+
+// HandleKey dispatches ev to the top screen and acts on the ScreenID it
+// returns, reporting whether the program should quit.
+func (sm *ScreenManager) HandleKey(ev termbox.Event) bool {
+	current := sm.top()
+	switch current.HandleKey(ev) {
+	case ScreenQuit:
+		return true
+	case ScreenPop:
+		sm.pop()
+	case ScreenTask:
+		if ms, ok := current.(*MainScreen); ok {
+			sm.push(newTaskScreen(ms.tm, ms.pendingTaskID))
+		}
+	case ScreenAbout:
+		sm.push(newAboutScreen())
+	}
+	return false
+}
+
+// drawLine writes s starting at (x, y), clipped to width, in the given
+// colors. Shared by every Screen's Render.
+func drawLine(x, y, width int, s string, fg, bg termbox.Attribute) {
+	for i, r := range s {
+		if x+i >= width {
+			break
+		}
+		termbox.SetCell(x+i, y, r, fg, bg)
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// parseTaskMetadata pulls +project, @context, and key:value tokens out of a
+// raw title typed by the user, returning the remaining plain title alongside
+// the structured fields extracted from it. This mirrors the todo.txt
+// conventions the ImportTodoTxt/ExportTodoTxt subsystem already speaks.
+func parseTaskMetadata(raw string) (title string, projects, contexts []string, tags map[string]string) {
+	tags = make(map[string]string)
+	var titleWords []string
+
+	for _, word := range strings.Fields(raw) {
+		switch {
+		case len(word) > 1 && word[0] == '+':
+			projects = append(projects, word[1:])
+		case len(word) > 1 && word[0] == '@':
+			contexts = append(contexts, word[1:])
+		case isMetadataToken(word):
+			kv := strings.SplitN(word, ":", 2)
+			tags[kv[0]] = kv[1]
+		default:
+			titleWords = append(titleWords, word)
+		}
+	}
+
+	return strings.Join(titleWords, " "), projects, contexts, tags
+}
+
+// formatTaskMetadata is parseTaskMetadata's inverse: it rebuilds the
+// token-bearing title a user would type, appending +project/@context/
+// key:value words back onto the plain title. Editors that let a task's
+// title be retyped (TaskScreen's title field) must seed their buffer from
+// this, not the bare title, or committing the edit unchanged would feed the
+// plain title back through parseTaskMetadata and silently drop the task's
+// structured fields.
+func formatTaskMetadata(title string, projects, contexts []string, tags map[string]string) string {
+	words := []string{title}
+	for _, p := range projects {
+		words = append(words, "+"+p)
+	}
+	for _, c := range contexts {
+		words = append(words, "@"+c)
+	}
+	for _, k := range sortedTagKeys(tags) {
+		words = append(words, k+":"+tags[k])
+	}
+	return strings.Join(words, " ")
+}
+
+// isMetadataToken reports whether word looks like a todo.txt key:value pair
+// rather than, say, a URL or a sentence ending in a colon.
+func isMetadataToken(word string) bool {
+	idx := strings.IndexByte(word, ':')
+	if idx <= 0 || idx >= len(word)-1 {
+		return false
+	}
+	return !strings.HasPrefix(word[idx+1:], "//")
+}
+
+// listSep joins encoded []string/map columns. Projects, contexts, and tag
+// values come from parseTaskMetadata as whitespace-separated todo.txt
+// tokens, so a token can never itself contain whitespace; the ASCII unit
+// separator is safe as a delimiter where a plain comma is not (commas are
+// valid inside a +project, @context, or key:value token).
+const listSep = "\x1f"
+
+// encodeStringList and decodeStringList store []string columns as a
+// listSep-joined TEXT value.
+func encodeStringList(items []string) string {
+	return strings.Join(items, listSep)
+}
+
+func decodeStringList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, listSep)
+}
+
+// encodeTags and decodeTags do the same for the key:value tag map, with
+// keys sorted so the stored representation is stable across saves.
+func encodeTags(tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+":"+tags[k])
+	}
+	return strings.Join(parts, listSep)
+}
+
+func decodeTags(s string) map[string]string {
+	tags := make(map[string]string)
+	if s == "" {
+		return tags
+	}
+	for _, part := range strings.Split(s, listSep) {
+		kv := strings.SplitN(part, ":", 2)
+		if len(kv) == 2 {
+			tags[kv[0]] = kv[1]
+		}
+	}
+	return tags
+}
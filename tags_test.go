@@ -0,0 +1,75 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStringListRoundTripWithCommas(t *testing.T) {
+	items := []string{"stuff,milk", "home,store"}
+	got := decodeStringList(encodeStringList(items))
+	if !reflect.DeepEqual(got, items) {
+		t.Fatalf("round-trip mismatch: got %v, want %v", got, items)
+	}
+}
+
+func TestTagsRoundTripWithCommas(t *testing.T) {
+	tags := map[string]string{"note": "a,b", "due": "2026-01-01,ish"}
+	got := decodeTags(encodeTags(tags))
+	if !reflect.DeepEqual(got, tags) {
+		t.Fatalf("round-trip mismatch: got %v, want %v", got, tags)
+	}
+}
+
+func TestParseTaskMetadataCommaValues(t *testing.T) {
+	title, projects, contexts, tags := parseTaskMetadata("Buy +stuff,milk @store,home note:a,b")
+	if title != "Buy" {
+		t.Fatalf("title = %q, want %q", title, "Buy")
+	}
+	if !reflect.DeepEqual(projects, []string{"stuff,milk"}) {
+		t.Fatalf("projects = %v", projects)
+	}
+	if !reflect.DeepEqual(contexts, []string{"store,home"}) {
+		t.Fatalf("contexts = %v", contexts)
+	}
+	if !reflect.DeepEqual(tags, map[string]string{"note": "a,b"}) {
+		t.Fatalf("tags = %v", tags)
+	}
+
+	// The encode/decode round trip must not split these comma-bearing
+	// values back into multiple entries.
+	gotProjects := decodeStringList(encodeStringList(projects))
+	if !reflect.DeepEqual(gotProjects, projects) {
+		t.Fatalf("projects round-trip = %v, want %v", gotProjects, projects)
+	}
+}
+
+func TestParseTaskMetadataSkipsURLs(t *testing.T) {
+	title, _, _, tags := parseTaskMetadata("See http://foo for details")
+	if title != "See http://foo for details" {
+		t.Fatalf("title = %q, want URL left in title", title)
+	}
+	if len(tags) != 0 {
+		t.Fatalf("tags = %v, want none (URL should not be parsed as key:value)", tags)
+	}
+}
+
+func TestFormatTaskMetadataRoundTripsThroughParse(t *testing.T) {
+	title, projects, contexts, tags := "Deploy", []string{"ops"}, []string{"prod"}, map[string]string{"urgent": "yes"}
+
+	rebuilt := formatTaskMetadata(title, projects, contexts, tags)
+
+	gotTitle, gotProjects, gotContexts, gotTags := parseTaskMetadata(rebuilt)
+	if gotTitle != title {
+		t.Fatalf("title = %q, want %q", gotTitle, title)
+	}
+	if !reflect.DeepEqual(gotProjects, projects) {
+		t.Fatalf("projects = %v, want %v", gotProjects, projects)
+	}
+	if !reflect.DeepEqual(gotContexts, contexts) {
+		t.Fatalf("contexts = %v, want %v", gotContexts, contexts)
+	}
+	if !reflect.DeepEqual(gotTags, tags) {
+		t.Fatalf("tags = %v, want %v", gotTags, tags)
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nsf/termbox-go"
+)
+
+// TaskScreen shows one task's full detail - title, priority, projects,
+// contexts, due date, notes, and its subtask list - and owns editing it.
+// It replaces the list's old single-line editMode with per-field edit
+// prompts, reached with Enter from MainScreen.
+type TaskScreen struct {
+	tm     *TaskManager
+	taskID int
+
+	editField string // "", "title", "notes", "due"
+	buffer    string
+}
+
+func newTaskScreen(tm *TaskManager, taskID int) *TaskScreen {
+	return &TaskScreen{tm: tm, taskID: taskID}
+}
+
+func (s *TaskScreen) Enter() {}
+
+func (s *TaskScreen) Leave() {
+	s.editField = ""
+	s.buffer = ""
+}
+
+// task looks up the current state of the task this screen is showing; it
+// can return nil if the task was deleted (e.g. via undo) while this screen
+// was open.
+func (s *TaskScreen) task() *Task {
+	s.tm.mu.RLock()
+	defer s.tm.mu.RUnlock()
+	return findTaskByID(s.tm.tasks, s.taskID)
+}
+
+func (s *TaskScreen) Render(width, height int) {
+	termbox.Clear(termbox.ColorDefault, termbox.ColorDefault)
+
+	task := s.task()
+	if task == nil {
+		drawLine(0, 0, width, "Task no longer exists - Esc to go back", termbox.ColorWhite|termbox.AttrBold, termbox.ColorRed)
+		termbox.Flush()
+		return
+	}
+
+	header := fmt.Sprintf("Task #%d - e: edit title/priority, n: edit notes (Ctrl-J: newline), D: edit due date, Esc: back", task.ID)
+	drawLine(0, 0, width, header, termbox.ColorWhite|termbox.AttrBold, termbox.ColorBlue)
+
+	y := 2
+	checkbox := "[ ]"
+	if task.Completed {
+		checkbox = "[x]"
+	}
+	drawLine(0, y, width, fmt.Sprintf("%s %s", checkbox, task.Title), termbox.ColorDefault, termbox.ColorDefault)
+	y++
+	drawLine(0, y, width, fmt.Sprintf("Priority: %d", task.Priority), termbox.ColorDefault, termbox.ColorDefault)
+	y++
+	if len(task.Projects) > 0 {
+		drawLine(0, y, width, "Projects: "+strings.Join(task.Projects, ", "), termbox.ColorDefault, termbox.ColorDefault)
+		y++
+	}
+	if len(task.Contexts) > 0 {
+		drawLine(0, y, width, "Contexts: "+strings.Join(task.Contexts, ", "), termbox.ColorDefault, termbox.ColorDefault)
+		y++
+	}
+	if len(task.Tags) > 0 {
+		var tags []string
+		for _, k := range sortedTagKeys(task.Tags) {
+			tags = append(tags, k+":"+task.Tags[k])
+		}
+		drawLine(0, y, width, "Tags: "+strings.Join(tags, ", "), termbox.ColorDefault, termbox.ColorDefault)
+		y++
+	}
+	due := "none"
+	if task.DueDate != nil {
+		due = task.DueDate.Format(todoTxtDateFormat)
+	}
+	drawLine(0, y, width, "Due: "+due, termbox.ColorDefault, termbox.ColorDefault)
+	y += 2
+
+	drawLine(0, y, width, "Notes:", termbox.ColorDefault, termbox.ColorDefault)
+	y++
+	if task.Notes == "" {
+		drawLine(2, y, width, "(none)", termbox.ColorDefault, termbox.ColorDefault)
+		y++
+	} else {
+		for _, line := range strings.Split(task.Notes, "\n") {
+			drawLine(2, y, width, line, termbox.ColorDefault, termbox.ColorDefault)
+			y++
+		}
+	}
+	y++
+
+	drawLine(0, y, width, fmt.Sprintf("Subtasks (%d):", len(task.Children)), termbox.ColorDefault, termbox.ColorDefault)
+	y++
+	for _, child := range task.Children {
+		childBox := "[ ]"
+		if child.Completed {
+			childBox = "[x]"
+		}
+		drawLine(2, y, width, fmt.Sprintf("%s %s (P:%d)", childBox, child.Title, child.Priority), termbox.ColorDefault, termbox.ColorDefault)
+		y++
+	}
+
+	if s.editField != "" {
+		editY := height - 2
+		for i, line := range strings.Split(s.buffer, "\n") {
+			prefix := fmt.Sprintf("Edit %s: ", s.editField)
+			if i > 0 {
+				prefix = strings.Repeat(" ", len(prefix))
+			}
+			drawLine(0, editY+i, width, prefix+line, termbox.ColorBlack, termbox.ColorYellow)
+		}
+	}
+
+	termbox.Flush()
+}
+
+func (s *TaskScreen) HandleKey(ev termbox.Event) ScreenID {
+	if s.editField != "" {
+		return s.handleEdit(ev)
+	}
+
+	if ev.Key == termbox.KeyEsc {
+		return ScreenPop
+	}
+
+	task := s.task()
+	if task == nil {
+		return ScreenNone
+	}
+
+	switch ev.Ch {
+	case 'e':
+		s.editField = "title"
+		s.buffer = fmt.Sprintf("%s:%d", formatTaskMetadata(task.Title, task.Projects, task.Contexts, task.Tags), task.Priority)
+	case 'n':
+		s.editField = "notes"
+		s.buffer = task.Notes
+	case 'D':
+		s.editField = "due"
+		if task.DueDate != nil {
+			s.buffer = task.DueDate.Format(todoTxtDateFormat)
+		}
+	}
+	return ScreenNone
+}
+
+func (s *TaskScreen) handleEdit(ev termbox.Event) ScreenID {
+	switch ev.Key {
+	case termbox.KeyEsc:
+		s.editField = ""
+		s.buffer = ""
+	case termbox.KeyEnter:
+		s.commitEdit()
+		s.editField = ""
+		s.buffer = ""
+	case termbox.KeyBackspace, termbox.KeyBackspace2:
+		if len(s.buffer) > 0 {
+			s.buffer = s.buffer[:len(s.buffer)-1]
+		}
+	case termbox.KeyCtrlJ:
+		if s.editField == "notes" {
+			s.buffer += "\n"
+		}
+	case termbox.KeySpace:
+		s.buffer += " "
+	default:
+		if ev.Ch != 0 {
+			s.buffer += string(ev.Ch)
+		}
+	}
+	return ScreenNone
+}
+
+// commitEdit applies s.buffer to whichever field is being edited.
+func (s *TaskScreen) commitEdit() {
+	switch s.editField {
+	case "title":
+		// Split on the last colon, not the first: the buffer is
+		// "<title, possibly with key:value tags>:<priority>", and a
+		// first-colon split would cut a tag's key:value apart instead of
+		// isolating the trailing priority digits.
+		title := s.buffer
+		priority := 50
+		if task := s.task(); task != nil {
+			priority = task.Priority
+		}
+		if idx := strings.LastIndex(s.buffer, ":"); idx >= 0 {
+			if p, err := strconv.Atoi(strings.TrimSpace(s.buffer[idx+1:])); err == nil {
+				title = s.buffer[:idx]
+				priority = p
+			}
+		}
+		title = strings.TrimSpace(title)
+		if priority < 1 {
+			priority = 1
+		}
+		if priority > 100 {
+			priority = 100
+		}
+		s.tm.queueUpdateTask(s.taskID, title, priority)
+	case "notes":
+		s.tm.queueUpdateTaskNotes(s.taskID, s.buffer)
+	case "due":
+		var due *time.Time
+		if trimmed := strings.TrimSpace(s.buffer); trimmed != "" {
+			if d, err := time.Parse(todoTxtDateFormat, trimmed); err == nil {
+				due = &d
+			}
+		}
+		s.tm.queueUpdateTaskDueDate(s.taskID, due)
+	}
+}
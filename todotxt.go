@@ -0,0 +1,293 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// todo.txt priority letters run A (highest) through Z (lowest); we spread
+// them evenly across the 1-100 scale used internally so repeated
+// export/import round trips don't drift.
+const todoTxtDateFormat = "2006-01-02"
+
+// priorityToLetter maps a 1-100 priority onto the A-Z range used by the
+// todo.txt format.
+func priorityToLetter(priority int) byte {
+	idx := (100 - priority) * 25 / 99
+	if idx < 0 {
+		idx = 0
+	}
+	if idx > 25 {
+		idx = 25
+	}
+	return byte('A' + idx)
+}
+
+// letterToPriority is the inverse of priorityToLetter. priorityToLetter maps
+// several priorities onto the same letter, so the inverse picks the midpoint
+// of that letter's bucket rather than its low (or high) edge; otherwise
+// feeding the result back through priorityToLetter could land on a
+// neighboring letter and every export/import cycle would walk priorities
+// toward A.
+func letterToPriority(letter byte) int {
+	idx := int(letter - 'A')
+	if idx < 0 {
+		idx = 0
+	}
+	if idx > 25 {
+		idx = 25
+	}
+
+	lo := (99*idx + 24) / 25 // ceil(99*idx/25): smallest (100-priority) in this bucket
+	hi := (99*(idx+1) - 1) / 25
+	if idx == 25 {
+		hi = 99 // bucket 25 is open-ended below; priority bottoms out at 1
+	}
+	return 100 - (lo+hi)/2
+}
+
+// todoTxtLine is the parsed form of one todo.txt line before it's committed
+// to the database. origID/parentOrig track the hierarchy recorded via the
+// parent:<id> metadata key so it can be rebuilt once every task has a new
+// database id.
+type todoTxtLine struct {
+	title      string
+	priority   int
+	createdAt  time.Time
+	completed  bool
+	projects   []string
+	contexts   []string
+	tags       map[string]string
+	origID     int
+	idKnown    bool
+	parentOrig int
+	hasParent  bool
+}
+
+// ExportTodoTxt writes every task to path in todo.txt format, one line per
+// task (subtasks included): completion marker, priority, date, title, then
+// +project/@context/key:value tokens rebuilt from the task's structured
+// fields, plus id/parent metadata so the hierarchy survives the round trip.
+func (tm *TaskManager) ExportTodoTxt(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	for _, task := range flattenForExport(tm.tasks) {
+		if _, err := fmt.Fprintln(w, formatTodoTxtLine(task)); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// flattenForExport walks the task tree depth-first so parents are written
+// before their children (not required for import, but easier to read).
+func flattenForExport(tasks []*Task) []*Task {
+	var out []*Task
+	for _, t := range tasks {
+		out = append(out, t)
+		out = append(out, flattenForExport(t.Children)...)
+	}
+	return out
+}
+
+func formatTodoTxtLine(task *Task) string {
+	var b strings.Builder
+	if task.Completed {
+		b.WriteString("x ")
+	}
+	fmt.Fprintf(&b, "(%c) %s %s", priorityToLetter(task.Priority), task.CreatedAt.Format(todoTxtDateFormat), task.Title)
+	for _, p := range task.Projects {
+		fmt.Fprintf(&b, " +%s", p)
+	}
+	for _, c := range task.Contexts {
+		fmt.Fprintf(&b, " @%s", c)
+	}
+	for _, k := range sortedTagKeys(task.Tags) {
+		fmt.Fprintf(&b, " %s:%s", k, task.Tags[k])
+	}
+	fmt.Fprintf(&b, " id:%d", task.ID)
+	if task.ParentID != nil {
+		fmt.Fprintf(&b, " parent:%d", *task.ParentID)
+	}
+	return b.String()
+}
+
+func sortedTagKeys(tags map[string]string) []string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ImportTodoTxt reads path and inserts each line as a task, restoring
+// priority, creation date, and parent/child relationships recorded via the
+// parent:<id> metadata key. Tasks are inserted in two passes: the first
+// gives every line a fresh database id, the second wires up parent_id now
+// that the old-id -> new-id mapping is complete. It runs as a queued
+// Scheduler op (see queueImportTodoTxt) and checks ctx itself like addTask
+// does. Parsing and the per-line db.ExecContext calls - the bulk of a large
+// import's runtime - all run with tm.mu unlocked (see the comment on
+// TaskManager.mu), re-checking ctx every iteration so Ctrl-G stops the
+// import after its current row instead of running to completion; tm.mu is
+// only taken at the end to commit the result.
+func (tm *TaskManager) ImportTodoTxt(ctx context.Context, path string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var lines []todoTxtLine
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		raw := strings.TrimSpace(scanner.Text())
+		if raw == "" {
+			continue
+		}
+		line, err := parseTodoTxtLine(raw)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	// Lines from a foreign todo.txt file won't carry our id: tag; give them
+	// a value that can't collide with a real database id so parent:
+	// references among them still resolve within this import.
+	for i := range lines {
+		if !lines[i].idKnown {
+			lines[i].origID = -(i + 1)
+		}
+	}
+
+	idMap := make(map[int]int64) // origID -> new DB id
+	for i := range lines {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		completed := 0
+		if lines[i].completed {
+			completed = 1
+		}
+		res, err := tm.db.ExecContext(ctx,
+			"INSERT INTO tasks (title, priority, created_at, projects, contexts, tags, completed) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			lines[i].title, lines[i].priority, lines[i].createdAt.Format("2006-01-02 15:04:05"),
+			encodeStringList(lines[i].projects), encodeStringList(lines[i].contexts), encodeTags(lines[i].tags), completed,
+		)
+		if err != nil {
+			return err
+		}
+		newID, _ := res.LastInsertId()
+		idMap[lines[i].origID] = newID
+	}
+
+	for _, line := range lines {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !line.hasParent {
+			continue
+		}
+		parentID, ok := idMap[line.parentOrig]
+		if !ok {
+			continue
+		}
+		if _, err := tm.db.ExecContext(ctx, "UPDATE tasks SET parent_id = ? WHERE id = ?", parentID, idMap[line.origID]); err != nil {
+			return err
+		}
+	}
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	tm.logf(LogInfo, "Imported %d tasks from %s", len(lines), path)
+	tm.loadTasksLocked()
+	tm.rebuildFlatView()
+	return nil
+}
+
+// queueImportTodoTxt queues ImportTodoTxt on the scheduler and returns
+// immediately so the UI thread isn't blocked on the import (see
+// queueAddTask).
+func (tm *TaskManager) queueImportTodoTxt(path string) *Condition {
+	return tm.queue(func(ctx context.Context) error {
+		return tm.ImportTodoTxt(ctx, path)
+	})
+}
+
+// parseTodoTxtLine splits off the leading (X) priority and date tokens and
+// the trailing id:/parent: metadata tokens, leaving everything else as the
+// task title untouched.
+func parseTodoTxtLine(line string) (todoTxtLine, error) {
+	var p todoTxtLine
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return p, fmt.Errorf("empty todo.txt line")
+	}
+
+	idx := 0
+	if fields[idx] == "x" {
+		p.completed = true
+		idx++
+	}
+
+	p.priority = 50
+	if idx < len(fields) && len(fields[idx]) == 3 && fields[idx][0] == '(' && fields[idx][2] == ')' {
+		p.priority = letterToPriority(fields[idx][1])
+		idx++
+	}
+
+	p.createdAt = time.Now()
+	if idx < len(fields) {
+		if d, err := time.Parse(todoTxtDateFormat, fields[idx]); err == nil {
+			p.createdAt = d
+			idx++
+		}
+	}
+
+	var titleFields []string
+	for _, f := range fields[idx:] {
+		switch {
+		case strings.HasPrefix(f, "id:"):
+			if n, err := strconv.Atoi(strings.TrimPrefix(f, "id:")); err == nil {
+				p.origID = n
+				p.idKnown = true
+			}
+		case strings.HasPrefix(f, "parent:"):
+			if n, err := strconv.Atoi(strings.TrimPrefix(f, "parent:")); err == nil {
+				p.parentOrig = n
+				p.hasParent = true
+			}
+		default:
+			titleFields = append(titleFields, f)
+		}
+	}
+
+	title, projects, contexts, tags := parseTaskMetadata(strings.Join(titleFields, " "))
+	p.title, p.projects, p.contexts, p.tags = title, projects, contexts, tags
+	if p.title == "" {
+		return p, fmt.Errorf("todo.txt line has no title: %q", line)
+	}
+	return p, nil
+}
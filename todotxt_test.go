@@ -0,0 +1,26 @@
+package main
+
+import "testing"
+
+func TestPriorityLetterRoundTripIsStable(t *testing.T) {
+	for letter := byte('A'); letter <= 'Z'; letter++ {
+		p := letterToPriority(letter)
+		if p < 1 || p > 100 {
+			t.Fatalf("letterToPriority(%c) = %d, want 1-100", letter, p)
+		}
+		if got := priorityToLetter(p); got != letter {
+			t.Fatalf("priorityToLetter(letterToPriority(%c)) = %c, want %c", letter, got, letter)
+		}
+	}
+}
+
+func TestPriorityLetterRoundTripDoesNotDrift(t *testing.T) {
+	priority := letterToPriority(priorityToLetter(70)) // settle into a bucket once
+	for i := 0; i < 20; i++ {
+		next := letterToPriority(priorityToLetter(priority))
+		if next != priority {
+			t.Fatalf("priority drifted from %d to %d on cycle %d, want stable", priority, next, i)
+		}
+		priority = next
+	}
+}